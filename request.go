@@ -2,13 +2,20 @@ package request
 
 import (
 	"bytes"
+	"context"
 	"crypto/tls"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
 	"io/ioutil"
+	"math/rand"
+	"mime/multipart"
+	"net"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -54,21 +61,110 @@ func (header HeaderType) Clear() {
 
 // Request 構造体は、リクエストを送信する構造体
 type Request struct {
-	URL      string      // リクエスト送信先のURL
-	Username string      // 送信先URLのベーシック認証ユーザID
-	Password string      // 送信先URLのベーシック認証パスワード
-	Timeout  int         // 送信先URLのタイムアウト時間
-	Insecure bool        // 送信先URLが自己証明書の場合でも、送信できるようにするフラグ
-	Proxy    Proxy       // プロキシサーバ設定情報
-	values   *url.Values // 送信するデータ
-	header   HeaderType  // HTTPヘッダ
+	URL      string          // リクエスト送信先のURL
+	Username string          // 送信先URLのベーシック認証ユーザID
+	Password string          // 送信先URLのベーシック認証パスワード
+	Timeout  int             // 送信先URLのタイムアウト時間(秒)。未設定の場合は10秒 ※以前のバージョンはミリ秒指定だったため、既存の呼び出し元は値の見直しが必要(後方互換なし)
+	Insecure bool            // 送信先URLが自己証明書の場合でも、送信できるようにするフラグ
+	Proxy    Proxy           // プロキシサーバ設定情報
+	Auth     Authenticator   // 認証情報を設定する Authenticator。設定時は Username/Password より優先される
+	Context  context.Context // リクエストのキャンセル、デッドラインを制御するコンテキスト
+
+	MaxRetries       int                              // リトライする最大回数。0の場合はリトライしない
+	RetryBackoff     time.Duration                    // リトライ時の基準待機時間。未設定の場合は500ミリ秒
+	RetryOn          func(*http.Response, error) bool // リトライするか否かを判定する関数。未設定の場合は defaultRetryOn を利用する
+	MaxResponseBytes int64                            // レスポンスボディの読み取り上限バイト数。0以下の場合は無制限
+	Body             interface{}                      // JSONとして送信する任意の値。設定時は values より優先される(ファイル登録時、multipart/form-data 指定時は送信されない)
+	values           *url.Values                      // 送信するデータ
+	header           HeaderType                       // HTTPヘッダ
+	files            []FileField                      // multipart/form-data で送信するファイル情報
+	body             []byte                           // Send で直近に読み取ったレスポンスボディ
+}
+
+// FileField 構造体は、multipart/form-data で送信するファイル1件分の情報を保持する構造体
+type FileField struct {
+	FieldName string    // フォームのフィールド名
+	FileName  string    // 送信するファイル名
+	Reader    io.Reader // ファイルの内容を読み取る io.Reader
+}
+
+// AddFile は、multipart/form-data で送信するファイルを追加する
+func (r *Request) AddFile(fieldName, fileName string, reader io.Reader) {
+	r.files = append(r.files, FileField{FieldName: fieldName, FileName: fileName, Reader: reader})
+}
+
+// Authenticator インターフェースは、リクエストへ認証情報を設定する振る舞いを定義する
+type Authenticator interface {
+	// Apply は、req へ認証情報を設定する
+	Apply(req *http.Request) error
+}
+
+// BasicAuth 構造体は、ベーシック認証情報を設定する Authenticator
+type BasicAuth struct {
+	Username string // ベーシック認証ユーザID
+	Password string // ベーシック認証パスワード
+}
+
+// Apply は、req へベーシック認証情報を設定する
+func (auth *BasicAuth) Apply(req *http.Request) error {
+	req.SetBasicAuth(auth.Username, auth.Password)
+	return nil
+}
+
+// BearerToken 構造体は、Authorization ヘッダへ Bearer トークンを設定する Authenticator
+type BearerToken struct {
+	Token string // Bearer トークン
+}
+
+// Apply は、req の Authorization ヘッダへ Bearer トークンを設定する
+func (auth *BearerToken) Apply(req *http.Request) error {
+	req.Header.Set("Authorization", "Bearer "+auth.Token)
+	return nil
+}
+
+// APIKeyHeader 構造体は、任意のヘッダへAPIキーを設定する Authenticator
+type APIKeyHeader struct {
+	Header string // APIキーを設定するヘッダ名。未設定の場合は X-Api-Key
+	Key    string // APIキー
+}
+
+// Apply は、req へAPIキーヘッダを設定する
+func (auth *APIKeyHeader) Apply(req *http.Request) error {
+	header := auth.Header
+	if header == "" {
+		header = "X-Api-Key"
+	}
+	req.Header.Set(header, auth.Key)
+	return nil
+}
+
+// TokenSource インターフェースは、OAuth2TokenSource がトークンを取得するために利用する
+type TokenSource interface {
+	// Token は、最新のアクセストークンを返却する
+	Token() (string, error)
+}
+
+// OAuth2TokenSource 構造体は、TokenSource からトークンを都度取得し、Bearer として設定する Authenticator
+type OAuth2TokenSource struct {
+	Source TokenSource // アクセストークンの取得元
+}
+
+// Apply は、Source から取得したトークンを Authorization ヘッダへ Bearer として設定する
+func (auth *OAuth2TokenSource) Apply(req *http.Request) error {
+	token, err := auth.Source.Token()
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	return nil
 }
 
 // Proxy 構造体は、プロキシサーバの情報を取り扱う構造体
 type Proxy struct {
-	URL      string // プロキシサーバのURL
+	URL      string // プロキシサーバのURL。socks5:// スキームを指定した場合はSOCKS5として扱う
 	Username string // プロキシサーバの認証ユーザID
 	Password string // プロキシサーバの認証パスワード
+	UseEnv   bool   // URL が未設定の場合に、環境変数からプロキシ設定を取得するフラグ
 }
 
 // Header は、HTTPヘッダのヘッダ情報を返却する
@@ -89,8 +185,14 @@ func (r *Request) Values() url.Values {
 
 // Request 関数は、リクエスト情報を作成する
 func (r *Request) Request(method, username, password string, data io.Reader) (*http.Request, error) {
+	// コンテキストが設定されていない場合は、context.Background() を利用する
+	ctx := r.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
 	// 指定されたURLへアクセスする *http.Request を生成する
-	req, err := http.NewRequest(method, r.URL, data)
+	req, err := http.NewRequestWithContext(ctx, method, r.URL, data)
 	if err != nil {
 		return nil, err
 	}
@@ -100,9 +202,17 @@ func (r *Request) Request(method, username, password string, data io.Reader) (*h
 		req.Header.Set(k, v)
 	}
 
-	// ベーシック認証の場合は、ユーザID、パスワードを設定
-	if username != "" && password != "" {
-		req.SetBasicAuth(username, password)
+	// Auth が設定されていない場合は、ユーザID、パスワードから BasicAuth を構築する
+	auth := r.Auth
+	if auth == nil && username != "" && password != "" {
+		auth = &BasicAuth{Username: username, Password: password}
+	}
+
+	// Authenticator が設定されている場合は、認証情報を設定する
+	if auth != nil {
+		if err := auth.Apply(req); err != nil {
+			return nil, err
+		}
 	}
 
 	// 生成したリクエスト情報構造体を返却する
@@ -112,19 +222,36 @@ func (r *Request) Request(method, username, password string, data io.Reader) (*h
 // Transport 構造体は、プロキシやHTTPSの設定情報をTransport構造体に設定する
 func (r *Request) Transport(req *http.Request) (*http.Transport, error) {
 	var transport = &http.Transport{}
+
+	switch {
+	// URLが未設定かつ環境変数利用フラグが立っている場合は、環境変数からプロキシ設定を取得する
+	case r.Proxy.URL == "" && r.Proxy.UseEnv:
+		transport.Proxy = http.ProxyFromEnvironment
 	// プロキシサーバを介する場合の設定
-	if r.Proxy.URL != "" {
-		// Transport にプロキシサーバ情報を登録
-		proxy, err := url.Parse(r.Proxy.URL)
+	case r.Proxy.URL != "":
+		proxyURL, err := url.Parse(r.Proxy.URL)
 		if err != nil {
 			return nil, err
 		}
-		transport.Proxy = http.ProxyURL(proxy)
+
+		if proxyURL.Scheme == "socks5" {
+			// SOCKS5プロキシの場合は、専用のDialContextで接続する
+			transport.DialContext = r.socks5DialContext(proxyURL)
+			break
+		}
+
+		// Transport にプロキシサーバ情報を登録
+		transport.Proxy = http.ProxyURL(proxyURL)
 		// プロキシサーバの認証設定
 		if r.Proxy.Username != "" && r.Proxy.Password != "" {
 			auth := r.Proxy.Username + ":" + r.Proxy.Password
 			basic := "Basic " + base64.StdEncoding.EncodeToString([]byte(auth))
-			req.Header.Add("Proxy-Ahthorization", basic)
+			// HTTPS先とのCONNECTトンネルを張る場合は、CONNECTリクエスト自体に認証情報を設定する
+			transport.ProxyConnectHeader = http.Header{"Proxy-Authorization": []string{basic}}
+			// HTTPプロキシ経由でHTTP先へ送信する場合は、CONNECTを経由しないため最終リクエストへ設定する
+			if strings.Index(r.URL, "https://") != 0 {
+				req.Header.Set("Proxy-Authorization", basic)
+			}
 		}
 	}
 
@@ -137,9 +264,198 @@ func (r *Request) Transport(req *http.Request) (*http.Transport, error) {
 	return transport, nil
 }
 
-// Send は、リクエスト情報をサーバへ送信する
-func (r *Request) Send(req *http.Request, transport *http.Transport) ([]byte, *http.Response, error) {
-	// タイムアウト時間を設定
+// socks5DialContext は、SOCKS5 プロキシ経由で接続するための DialContext 関数を生成する
+func (r *Request) socks5DialContext(proxyURL *url.URL) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		conn, err := (&net.Dialer{}).DialContext(ctx, "tcp", proxyURL.Host)
+		if err != nil {
+			return nil, err
+		}
+		if err := socks5Handshake(conn, r.Proxy.Username, r.Proxy.Password, addr); err != nil {
+			conn.Close()
+			return nil, err
+		}
+		return conn, nil
+	}
+}
+
+// socks5Handshake は、SOCKS5 のネゴシエーションと CONNECT コマンドを実行する(RFC 1928, RFC 1929)
+func socks5Handshake(conn net.Conn, username, password, addr string) error {
+	host, portStr, err := net.SplitHostPort(addr)
+	if err != nil {
+		return err
+	}
+	port, err := strconv.Atoi(portStr)
+	if err != nil {
+		return err
+	}
+	// ドメイン名のアドレスタイプは長さを1バイトで表現するため、255バイトを超えるホスト名は扱えない
+	if len(host) > 255 {
+		return fmt.Errorf("request: SOCKS5 hostname too long: %d bytes", len(host))
+	}
+
+	// 認証情報がある場合も、サーバが認証不要(0x00)を選択する可能性があるため両方を提示する
+	methods := []byte{0x00}
+	if username != "" && password != "" {
+		methods = []byte{0x00, 0x02}
+	}
+
+	greeting := append([]byte{0x05, byte(len(methods))}, methods...)
+	if _, err := conn.Write(greeting); err != nil {
+		return err
+	}
+
+	selected := make([]byte, 2)
+	if _, err := io.ReadFull(conn, selected); err != nil {
+		return err
+	}
+	if selected[0] != 0x05 {
+		return fmt.Errorf("request: unexpected SOCKS version: %d", selected[0])
+	}
+	if selected[1] == 0xff {
+		return fmt.Errorf("request: SOCKS5 server rejected all authentication methods")
+	}
+
+	// ユーザ名/パスワード認証が選択された場合は、RFC 1929 に従い認証する
+	if selected[1] == 0x02 {
+		authReq := []byte{0x01, byte(len(username))}
+		authReq = append(authReq, username...)
+		authReq = append(authReq, byte(len(password)))
+		authReq = append(authReq, password...)
+		if _, err := conn.Write(authReq); err != nil {
+			return err
+		}
+
+		authResp := make([]byte, 2)
+		if _, err := io.ReadFull(conn, authResp); err != nil {
+			return err
+		}
+		if authResp[1] != 0x00 {
+			return fmt.Errorf("request: SOCKS5 authentication failed")
+		}
+	} else if selected[1] != 0x00 {
+		return fmt.Errorf("request: SOCKS5 server selected unsupported auth method: %d", selected[1])
+	}
+
+	connectReq := []byte{0x05, 0x01, 0x00, 0x03, byte(len(host))}
+	connectReq = append(connectReq, host...)
+	connectReq = append(connectReq, byte(port>>8), byte(port))
+	if _, err := conn.Write(connectReq); err != nil {
+		return err
+	}
+
+	header := make([]byte, 4)
+	if _, err := io.ReadFull(conn, header); err != nil {
+		return err
+	}
+	if header[1] != 0x00 {
+		return fmt.Errorf("request: SOCKS5 CONNECT failed with code %d", header[1])
+	}
+
+	// バインドアドレスはCONNECTの戻り値としては使用しないため、読み捨てる
+	switch header[3] {
+	case 0x01: // IPv4
+		_, err = io.ReadFull(conn, make([]byte, net.IPv4len+2))
+	case 0x03: // ドメイン名
+		lenBuf := make([]byte, 1)
+		if _, err = io.ReadFull(conn, lenBuf); err == nil {
+			_, err = io.ReadFull(conn, make([]byte, int(lenBuf[0])+2))
+		}
+	case 0x04: // IPv6
+		_, err = io.ReadFull(conn, make([]byte, net.IPv6len+2))
+	default:
+		err = fmt.Errorf("request: unknown SOCKS5 address type: %d", header[3])
+	}
+
+	return err
+}
+
+// defaultRetryOn は、RetryOn が未設定の場合に利用する既定の判定関数
+// 429, 502, 503, 504 のステータスコード、または通信エラー発生時に true を返却する
+// ただし、コンテキストのキャンセル・デッドライン超過はリトライしても回復しないため対象外とする
+func defaultRetryOn(res *http.Response, err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	if err != nil {
+		return true
+	}
+	if res == nil {
+		return false
+	}
+	switch res.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// retryAfterDuration は、レスポンスの Retry-After ヘッダから待機時間を算出する
+// ヘッダが存在しない、または解釈できない場合は ok=false を返却する
+func retryAfterDuration(res *http.Response) (time.Duration, bool) {
+	if res == nil {
+		return 0, false
+	}
+
+	value := res.Header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+
+	// delta-seconds 形式の場合
+	if secs, err := strconv.Atoi(value); err == nil {
+		return time.Duration(secs) * time.Second, true
+	}
+
+	// HTTP-date 形式の場合
+	if t, err := http.ParseTime(value); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d, true
+		}
+		return 0, true
+	}
+
+	return 0, false
+}
+
+// decorrelatedJitter は、前回の待機時間をもとに次回の待機時間を算出する
+func decorrelatedJitter(base, prev, cap time.Duration) time.Duration {
+	max := int64(prev) * 3
+	min := int64(base)
+	if max <= min {
+		max = min + 1
+	}
+	next := time.Duration(min + rand.Int63n(max-min))
+	if next > cap {
+		next = cap
+	}
+	return next
+}
+
+// prepareBody は、リクエストボディをバッファへ読み込み、リトライ時に読み直せるようにする
+func prepareBody(req *http.Request) error {
+	if req.Body == nil || req.GetBody != nil {
+		return nil
+	}
+
+	buf, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		return err
+	}
+	req.Body.Close()
+
+	req.GetBody = func() (io.ReadCloser, error) {
+		return ioutil.NopCloser(bytes.NewReader(buf)), nil
+	}
+	body, _ := req.GetBody()
+	req.Body = body
+
+	return nil
+}
+
+// do は、リトライ制御を含めてリクエストを送信し、*http.Response を返却する
+func (r *Request) do(req *http.Request, transport *http.Transport) (*http.Response, error) {
+	// タイムアウト時間を設定(秒単位)
 	var timeout = r.Timeout
 	if timeout <= 0 {
 		timeout = 10
@@ -147,23 +463,79 @@ func (r *Request) Send(req *http.Request, transport *http.Transport) ([]byte, *h
 
 	// リクエスト送信用インスタンスを生成
 	client := http.Client{
-		Timeout:   time.Duration(timeout) * time.Millisecond,
+		Timeout:   time.Duration(timeout) * time.Second,
 		Transport: transport,
 	}
 
+	// リトライに備え、ボディを読み直せるようにしておく
+	if err := prepareBody(req); err != nil {
+		return nil, err
+	}
+
+	retryOn := r.RetryOn
+	if retryOn == nil {
+		retryOn = defaultRetryOn
+	}
+
+	base := r.RetryBackoff
+	if base <= 0 {
+		base = 500 * time.Millisecond
+	}
+	const capBackoff = 30 * time.Second
+	prev := base
+
+	var res *http.Response
+	var err error
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 && req.GetBody != nil {
+			body, berr := req.GetBody()
+			if berr != nil {
+				return nil, berr
+			}
+			req.Body = body
+		}
+
+		res, err = client.Do(req)
+		if attempt >= r.MaxRetries || !retryOn(res, err) {
+			break
+		}
+		if res != nil {
+			res.Body.Close()
+		}
+
+		// Retry-After ヘッダが存在する場合は、その時間を優先する
+		wait, ok := retryAfterDuration(res)
+		if !ok {
+			wait = decorrelatedJitter(base, prev, capBackoff)
+			prev = wait
+		}
+		time.Sleep(wait)
+	}
+
+	return res, err
+}
+
+// Send は、リクエスト情報をサーバへ送信する
+func (r *Request) Send(req *http.Request, transport *http.Transport) ([]byte, *http.Response, error) {
 	// リクエストを送信し、結果を受け取る
-	res, err := client.Do(req)
+	res, err := r.do(req, transport)
 	if err != nil {
 		return nil, nil, err
 	}
 	// 結果取得後、必ずBodyをクローズ
 	defer res.Body.Close()
 
-	// 取得したデータを読み取る
-	buf, err := ioutil.ReadAll(res.Body)
+	// 取得したデータを読み取る。MaxResponseBytesが設定されている場合は、その上限までしか読み取らない
+	var body io.Reader = res.Body
+	if r.MaxResponseBytes > 0 {
+		body = io.LimitReader(res.Body, r.MaxResponseBytes)
+	}
+	buf, err := ioutil.ReadAll(body)
 	if err != nil {
 		return nil, nil, err
 	}
+	// ReadJSON が利用できるよう、直近のレスポンスボディとして保持しておく
+	r.body = buf
 
 	// 接続したが、正常に値を取得できなかった場合
 	if !(res.StatusCode >= 200 && res.StatusCode <= 299) {
@@ -173,8 +545,8 @@ func (r *Request) Send(req *http.Request, transport *http.Transport) ([]byte, *h
 	return buf, res, nil
 }
 
-// Get 関数は、GET メソッドでリクエスト情報を送信する
-func (r *Request) Get() ([]byte, *http.Response, error) {
+// prepareGet は、GETメソッドで送信する *http.Request と *http.Transport を構築する
+func (r *Request) prepareGet() (*http.Request, *http.Transport, error) {
 	// クエリパラメータが設定されている場合、パースする
 	if idx := strings.Index(r.URL, "?"); idx != -1 {
 		if u, err := url.Parse(r.URL); err == nil {
@@ -204,30 +576,105 @@ func (r *Request) Get() ([]byte, *http.Response, error) {
 		return nil, nil, err
 	}
 
+	return req, transport, nil
+}
+
+// Get 関数は、GET メソッドでリクエスト情報を送信する
+func (r *Request) Get() ([]byte, *http.Response, error) {
+	req, transport, err := r.prepareGet()
+	if err != nil {
+		return nil, nil, err
+	}
+
 	// リクエストを送信する
 	return r.Send(req, transport)
 }
 
+// GetWithContext 関数は、ctx を設定したうえでGETメソッドでリクエスト情報を送信する
+func (r *Request) GetWithContext(ctx context.Context) ([]byte, *http.Response, error) {
+	r.Context = ctx
+	return r.Get()
+}
+
+// GetStream 関数は、GETメソッドでリクエストを送信し、レスポンスボディをバッファせずに返却する
+func (r *Request) GetStream() (io.ReadCloser, *http.Response, error) {
+	req, transport, err := r.prepareGet()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	res, err := r.do(req, transport)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return res.Body, res, nil
+}
+
+// Download 関数は、GETメソッドでリクエストを送信し、レスポンスボディを dst へ書き込む
+func (r *Request) Download(dst io.Writer) (*http.Response, error) {
+	body, res, err := r.GetStream()
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	if _, err := io.Copy(dst, body); err != nil {
+		return res, err
+	}
+
+	// 接続したが、正常に値を取得できなかった場合
+	if !(res.StatusCode >= 200 && res.StatusCode <= 299) {
+		return res, &ResponseStatus{Code: res.StatusCode, Message: res.Status}
+	}
+
+	return res, nil
+}
+
 // Post は、POSTメソッドでサーバに情報を送信する
 func (r *Request) Post() ([]byte, *http.Response, error) {
 	return r.Submit("POST")
 }
 
+// PostWithContext 関数は、ctx を設定したうえでPOSTメソッドでサーバに情報を送信する
+func (r *Request) PostWithContext(ctx context.Context) ([]byte, *http.Response, error) {
+	r.Context = ctx
+	return r.Post()
+}
+
 // Put 関数は、PUTメソッドでリクエストを投げる
 func (r *Request) Put() ([]byte, *http.Response, error) {
 	return r.Submit("PUT")
 }
 
+// PutWithContext 関数は、ctx を設定したうえでPUTメソッドでリクエストを投げる
+func (r *Request) PutWithContext(ctx context.Context) ([]byte, *http.Response, error) {
+	r.Context = ctx
+	return r.Put()
+}
+
 // Delete 関数は、DELETE メソッドでリクエスト投げる
 func (r *Request) Delete() ([]byte, *http.Response, error) {
 	return r.Submit("DELETE")
 }
 
+// DeleteWithContext 関数は、ctx を設定したうえでDELETEメソッドでリクエスト投げる
+func (r *Request) DeleteWithContext(ctx context.Context) ([]byte, *http.Response, error) {
+	r.Context = ctx
+	return r.Delete()
+}
+
 // Patch 関数は、PATCHメソッドでリクエストを投げる
 func (r *Request) Patch() ([]byte, *http.Response, error) {
 	return r.Submit("PATCH")
 }
 
+// PatchWithContext 関数は、ctx を設定したうえでPATCHメソッドでリクエストを投げる
+func (r *Request) PatchWithContext(ctx context.Context) ([]byte, *http.Response, error) {
+	r.Context = ctx
+	return r.Patch()
+}
+
 // Submit は、指定されたメソッドでサーバへリクエストを送信する
 func (r *Request) Submit(method string) ([]byte, *http.Response, error) {
 	// クエリパラメータが設定されている場合、パースする
@@ -252,17 +699,32 @@ func (r *Request) Submit(method string) ([]byte, *http.Response, error) {
 
 	// 送信するデータを整形する
 	var data io.Reader
-	if r.values != nil {
-		switch r.header.Get("Content-Type") {
-		// JSON タイプを指定している場合
-		case "application/json", "text/json", "text/x-json":
-			if buf, err := json.Marshal(r.values); err == nil {
-				data = bytes.NewBuffer(buf)
-			}
-		// 上記以外の場合
-		default:
-			data = strings.NewReader(r.values.Encode())
+	switch {
+	// ファイルが登録されている、または multipart/form-data を明示している場合
+	case len(r.files) > 0 || r.Header().Get("Content-Type") == "multipart/form-data":
+		buf, contentType, err := r.multipartBody()
+		if err != nil {
+			return nil, nil, err
+		}
+		data = buf
+		r.Header().Add("Content-Type", contentType)
+	// Bodyが設定されている場合は、その値をそのままJSONとしてマーシャルする
+	// Content-Type がJSON系でなければ(未指定時の既定値を含む)、SetJSON と同様に application/json へ置き換える
+	case r.Body != nil:
+		buf, err := json.Marshal(r.Body)
+		if err != nil {
+			return nil, nil, err
 		}
+		data = bytes.NewBuffer(buf)
+		if !isJSONContentType(r.Header().Get("Content-Type")) {
+			r.Header().Add("Content-Type", "application/json")
+		}
+	case r.values != nil && isJSONContentType(r.Header().Get("Content-Type")):
+		if buf, err := json.Marshal(r.values); err == nil {
+			data = bytes.NewBuffer(buf)
+		}
+	case r.values != nil:
+		data = strings.NewReader(r.values.Encode())
 	}
 
 	// 送信するリクエスト情報を構築
@@ -285,17 +747,68 @@ func (r *Request) Submit(method string) ([]byte, *http.Response, error) {
 	return r.Send(req, transport)
 }
 
+// multipartBody は、フォームの値とファイル情報から multipart/form-data のボディを構築する
+func (r *Request) multipartBody() (*bytes.Buffer, string, error) {
+	buf := &bytes.Buffer{}
+	writer := multipart.NewWriter(buf)
+
+	// フォームの値を書き込む
+	if r.values != nil {
+		for k, v1 := range *r.values {
+			for _, v2 := range v1 {
+				if err := writer.WriteField(k, v2); err != nil {
+					return nil, "", err
+				}
+			}
+		}
+	}
+
+	// ファイルをパートとして書き込む
+	for _, f := range r.files {
+		part, err := writer.CreateFormFile(f.FieldName, f.FileName)
+		if err != nil {
+			return nil, "", err
+		}
+		if _, err := io.Copy(part, f.Reader); err != nil {
+			return nil, "", err
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, "", err
+	}
+
+	return buf, writer.FormDataContentType(), nil
+}
+
+// isJSONContentType は、Content-Type がJSON系であるかどうかを判定する
+func isJSONContentType(contentType string) bool {
+	switch contentType {
+	case "application/json", "text/json", "text/x-json":
+		return true
+	}
+	return false
+}
+
 // JSON は、JSON文字列を整形し、サーバへリクエストを飛ばす準備をする
 func (r *Request) JSON(j []byte) error {
-	values := make(map[string]string)
-	if err := json.Unmarshal(j, &values); err != nil {
+	var v interface{}
+	if err := json.Unmarshal(j, &v); err != nil {
 		return err
 	}
 
-	for k, v := range values {
-		r.Values().Add(k, v)
-	}
+	r.SetJSON(json.RawMessage(j))
+	return nil
+}
 
+// SetJSON は、任意の値を Body へ設定し、JSONとして送信する準備をする
+func (r *Request) SetJSON(v interface{}) {
+	r.Body = v
 	r.Header().Add("Content-Type", "application/json")
-	return nil
+}
+
+// ReadJSON は、Send(Get/Post/Put/Delete/Patch)で直前に読み取ったレスポンスボディを v へJSONとしてデコードする
+// GetStream、Download で受信したボディは保持されないため、デコード対象にならない
+func (r *Request) ReadJSON(v interface{}) error {
+	return json.Unmarshal(r.body, v)
 }
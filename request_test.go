@@ -1,15 +1,91 @@
 package request
 
 import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"strconv"
 	"strings"
 	"testing"
+	"time"
 )
 
+// newConnectProxy は、CONNECTリクエストをtargetへ中継する簡易プロキシをテスト用に起動する
+// Proxy-Authorization ヘッダがCONNECTリクエストへ正しく設定されているかを authOK で通知する
+func newConnectProxy(t *testing.T, target string, username, password string) (addr string, authOK <-chan bool, cleanup func()) {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ch := make(chan bool, 1)
+
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		reader := bufio.NewReader(conn)
+		req, err := http.ReadRequest(reader)
+		if err != nil {
+			ch <- false
+			return
+		}
+
+		expected := "Basic " + base64.StdEncoding.EncodeToString([]byte(username+":"+password))
+		ch <- req.Method == "CONNECT" && req.Header.Get("Proxy-Authorization") == expected
+
+		conn.Write([]byte("HTTP/1.1 200 Connection Established\r\n\r\n"))
+
+		targetConn, err := net.Dial("tcp", target)
+		if err != nil {
+			return
+		}
+		defer targetConn.Close()
+
+		done := make(chan struct{}, 2)
+		go func() { io.Copy(targetConn, reader); done <- struct{}{} }()
+		go func() { io.Copy(conn, targetConn); done <- struct{}{} }()
+		<-done
+	}()
+
+	return ln.Addr().String(), ch, func() { ln.Close() }
+}
+
+var multipartHandleFunc = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseMultipartForm(1 << 20); err != nil {
+		w.WriteHeader(500)
+		return
+	}
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		w.WriteHeader(500)
+		return
+	}
+	defer file.Close()
+
+	buf := make([]byte, header.Size)
+	file.Read(buf)
+
+	if r.FormValue("key") == "value" && header.Filename == "test.txt" && string(buf) == "hello file" {
+		fmt.Fprintf(w, "SUCCESS")
+	}
+})
+
 var handleFunc = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 	switch r.Method {
 	case "GET":
@@ -206,6 +282,488 @@ func Test__PUT_HTTPS_JSON_SUCCESS(t *testing.T) {
 	}
 }
 
+type greeting struct {
+	Message string `json:"message"`
+	Count   int    `json:"count"`
+}
+
+func Test__POST_HTTPS_BODY_WITHOUT_JSON_CONTENTTYPE(t *testing.T) {
+	// https サーバをテストで立てる
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Content-Type") != "application/json" {
+			w.WriteHeader(500)
+			return
+		}
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			w.WriteHeader(500)
+			return
+		}
+		var v map[string]string
+		if err := json.Unmarshal(body, &v); err != nil || v["a"] != "b" {
+			w.WriteHeader(500)
+			return
+		}
+		fmt.Fprintf(w, "SUCCESS")
+	}))
+	defer ts.Close()
+
+	// リクエスト送信用の構造体をセット
+	r := Request{
+		URL:      ts.URL,
+		Insecure: true,
+	}
+
+	// SetJSON を経由せず、Body を直接セットしても、JSONとしてマーシャルされ Content-Type も application/json になる
+	r.Body = map[string]string{"a": "b"}
+
+	// POSTメソッドでリクエストを送信
+	res, _, err := r.Post()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(res) != "SUCCESS" {
+		t.Fatal("ERROR - POST_HTTPS_BODY_WITHOUT_JSON_CONTENTTYPE Post()")
+	}
+}
+
+func Test__PUT_HTTPS_SETJSON_SUCCESS(t *testing.T) {
+	// https サーバをテストで立てる
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var g greeting
+		if err := json.NewDecoder(r.Body).Decode(&g); err != nil {
+			w.WriteHeader(500)
+			return
+		}
+		if g.Message == "hello" && g.Count == 3 {
+			fmt.Fprintf(w, `{"message":"world","count":4}`)
+		}
+	}))
+	defer ts.Close()
+
+	// リクエスト送信用の構造体をセット
+	r := Request{
+		URL:      ts.URL,
+		Insecure: true,
+	}
+
+	// 任意のJSON値を送信データとしてセット
+	r.SetJSON(greeting{Message: "hello", Count: 3})
+
+	// PUTメソッドでリクエストを送信
+	res, _, err := r.Put()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(res) == "" {
+		t.Fatal("ERROR - PUT_HTTPS_SETJSON_SUCCESS Put()")
+	}
+
+	var g greeting
+	if err := r.ReadJSON(&g); err != nil {
+		t.Fatal(err)
+	}
+	if g.Message != "world" || g.Count != 4 {
+		t.Fatalf("ERROR - PUT_HTTPS_SETJSON_SUCCESS ReadJSON() = %+v", g)
+	}
+}
+
+func Test__GET_HTTPS_PROXY_CONNECT_AUTH_SUCCESS(t *testing.T) {
+	// https サーバをテストで立てる
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "SUCCESS")
+	}))
+	defer ts.Close()
+
+	targetURL, err := url.Parse(ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	proxyAddr, authOK, cleanup := newConnectProxy(t, targetURL.Host, "proxyuser", "proxypass")
+	defer cleanup()
+
+	// リクエスト送信用の構造体をセット
+	r := Request{
+		URL:      ts.URL,
+		Insecure: true,
+		Proxy: Proxy{
+			URL:      "http://" + proxyAddr,
+			Username: "proxyuser",
+			Password: "proxypass",
+		},
+	}
+
+	// GETメソッドでリクエストを送信
+	res, _, err := r.Get()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(res) != "SUCCESS" {
+		t.Fatal("ERROR - GET_HTTPS_PROXY_CONNECT_AUTH_SUCCESS Get()")
+	}
+	if ok := <-authOK; !ok {
+		t.Fatal("ERROR - GET_HTTPS_PROXY_CONNECT_AUTH_SUCCESS Proxy-Authorization header mismatch")
+	}
+}
+
+// newSocks5Proxy は、SOCKS5プロキシをテスト用に起動し、すべてのCONNECT先を target へ中継する
+// requireAuth が true の場合は、ユーザ名/パスワード認証(RFC 1929)を要求し username/password と照合する
+func newSocks5Proxy(t *testing.T, target string, requireAuth bool, username, password string) (addr string, cleanup func()) {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		// ネゴシエーション
+		greeting := make([]byte, 2)
+		if _, err := io.ReadFull(conn, greeting); err != nil {
+			return
+		}
+		methods := make([]byte, greeting[1])
+		if _, err := io.ReadFull(conn, methods); err != nil {
+			return
+		}
+
+		if requireAuth {
+			conn.Write([]byte{0x05, 0x02})
+
+			authHeader := make([]byte, 2)
+			if _, err := io.ReadFull(conn, authHeader); err != nil {
+				return
+			}
+			user := make([]byte, authHeader[1])
+			if _, err := io.ReadFull(conn, user); err != nil {
+				return
+			}
+			passLen := make([]byte, 1)
+			if _, err := io.ReadFull(conn, passLen); err != nil {
+				return
+			}
+			pass := make([]byte, passLen[0])
+			if _, err := io.ReadFull(conn, pass); err != nil {
+				return
+			}
+
+			if string(user) == username && string(pass) == password {
+				conn.Write([]byte{0x01, 0x00})
+			} else {
+				conn.Write([]byte{0x01, 0x01})
+				return
+			}
+		} else {
+			conn.Write([]byte{0x05, 0x00})
+		}
+
+		// CONNECTリクエストの読み取り(アドレスタイプは ATYP=0x03 のドメイン名のみサポート)
+		header := make([]byte, 5)
+		if _, err := io.ReadFull(conn, header); err != nil {
+			return
+		}
+		if _, err := io.ReadFull(conn, make([]byte, int(header[4])+2)); err != nil {
+			return
+		}
+
+		conn.Write([]byte{0x05, 0x00, 0x00, 0x01, 0, 0, 0, 0, 0, 0})
+
+		targetConn, err := net.Dial("tcp", target)
+		if err != nil {
+			return
+		}
+		defer targetConn.Close()
+
+		done := make(chan struct{}, 2)
+		go func() { io.Copy(targetConn, conn); done <- struct{}{} }()
+		go func() { io.Copy(conn, targetConn); done <- struct{}{} }()
+		<-done
+	}()
+
+	return ln.Addr().String(), func() { ln.Close() }
+}
+
+func Test__GET_HTTPS_SOCKS5_PROXY_SUCCESS(t *testing.T) {
+	// https サーバをテストで立てる
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "SUCCESS")
+	}))
+	defer ts.Close()
+
+	targetURL, err := url.Parse(ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	proxyAddr, cleanup := newSocks5Proxy(t, targetURL.Host, false, "", "")
+	defer cleanup()
+
+	// リクエスト送信用の構造体をセット
+	r := Request{
+		URL:      ts.URL,
+		Insecure: true,
+		Proxy:    Proxy{URL: "socks5://" + proxyAddr},
+	}
+
+	// GETメソッドでリクエストを送信
+	res, _, err := r.Get()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(res) != "SUCCESS" {
+		t.Fatal("ERROR - GET_HTTPS_SOCKS5_PROXY_SUCCESS Get()")
+	}
+}
+
+func Test__GET_HTTPS_SOCKS5_PROXY_AUTH_SUCCESS(t *testing.T) {
+	// https サーバをテストで立てる
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "SUCCESS")
+	}))
+	defer ts.Close()
+
+	targetURL, err := url.Parse(ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	proxyAddr, cleanup := newSocks5Proxy(t, targetURL.Host, true, "socksuser", "sockspass")
+	defer cleanup()
+
+	// リクエスト送信用の構造体をセット
+	r := Request{
+		URL:      ts.URL,
+		Insecure: true,
+		Proxy: Proxy{
+			URL:      "socks5://" + proxyAddr,
+			Username: "socksuser",
+			Password: "sockspass",
+		},
+	}
+
+	// GETメソッドでリクエストを送信
+	res, _, err := r.Get()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(res) != "SUCCESS" {
+		t.Fatal("ERROR - GET_HTTPS_SOCKS5_PROXY_AUTH_SUCCESS Get()")
+	}
+}
+
+func Test__GET_HTTPS_SOCKS5_PROXY_LONG_HOSTNAME(t *testing.T) {
+	// 256バイトを超えるホスト名を付与し、SOCKS5ハンドシェイクがエラーを返すことを確認する
+	longHost := strings.Repeat("a", 256) + ".example.com"
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	r := Request{
+		URL:      "https://" + longHost + ":443/",
+		Insecure: true,
+		Proxy:    Proxy{URL: "socks5://" + ln.Addr().String()},
+	}
+
+	if _, _, err := r.Get(); err == nil {
+		t.Fatal("ERROR - GET_HTTPS_SOCKS5_PROXY_LONG_HOSTNAME Get()")
+	}
+}
+
+func Test__GET_HTTPS_DOWNLOAD_SUCCESS(t *testing.T) {
+	// https サーバをテストで立てる
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "SUCCESS")
+	}))
+	defer ts.Close()
+
+	// リクエスト送信用の構造体をセット
+	r := Request{
+		URL:      ts.URL,
+		Insecure: true,
+	}
+
+	var buf bytes.Buffer
+	if _, err := r.Download(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != "SUCCESS" {
+		t.Fatal("ERROR - GET_HTTPS_DOWNLOAD_SUCCESS Download()")
+	}
+}
+
+func Test__GET_HTTPS_MAX_RESPONSE_BYTES(t *testing.T) {
+	// https サーバをテストで立てる
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "SUCCESS")
+	}))
+	defer ts.Close()
+
+	// リクエスト送信用の構造体をセット
+	r := Request{
+		URL:              ts.URL,
+		Insecure:         true,
+		MaxResponseBytes: 3,
+	}
+
+	res, _, err := r.Get()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(res) != "SUC" {
+		t.Fatalf("ERROR - GET_HTTPS_MAX_RESPONSE_BYTES res = %q, want %q", res, "SUC")
+	}
+}
+
+func Test__GET_HTTPS_RETRY_CONTEXT_CANCEL_NO_RETRY(t *testing.T) {
+	// https サーバをテストで立てる
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "SUCCESS")
+	}))
+	defer ts.Close()
+
+	// リクエスト送信用の構造体をセット
+	r := Request{
+		URL:          ts.URL,
+		Insecure:     true,
+		MaxRetries:   3,
+		RetryBackoff: 200 * time.Millisecond,
+	}
+
+	// 即座にキャンセルされる context を設定する
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	start := time.Now()
+	if _, _, err := r.GetWithContext(ctx); err == nil {
+		t.Fatal("ERROR - GET_HTTPS_RETRY_CONTEXT_CANCEL_NO_RETRY GetWithContext()")
+	}
+	if elapsed := time.Since(start); elapsed > 200*time.Millisecond {
+		t.Fatalf("ERROR - GET_HTTPS_RETRY_CONTEXT_CANCEL_NO_RETRY took %s, want fast failure without retry", elapsed)
+	}
+}
+
+func Test__GET_HTTPS_RETRY_SUCCESS(t *testing.T) {
+	var count int
+
+	// 503 を2回返した後、3回目に成功するサーバをテストで立てる
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		count++
+		if count <= 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprintf(w, "SUCCESS")
+	}))
+	defer ts.Close()
+
+	// リクエスト送信用の構造体をセット
+	r := Request{
+		URL:          ts.URL,
+		Insecure:     true,
+		MaxRetries:   2,
+		RetryBackoff: 10 * time.Millisecond,
+	}
+
+	// GETメソッドでリクエストを送信
+	res, _, err := r.Get()
+	// エラーが発生した場合は、Fatalとする
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(res) != "SUCCESS" {
+		t.Fatal("ERROR - GET_HTTPS_RETRY_SUCCESS Get()")
+	}
+	if count != 3 {
+		t.Fatalf("ERROR - GET_HTTPS_RETRY_SUCCESS count = %d, want 3", count)
+	}
+}
+
+func Test__GET_HTTPS_BEARER_SUCCESS(t *testing.T) {
+	// https サーバをテストで立てる
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") == "Bearer token123" {
+			fmt.Fprintf(w, "SUCCESS")
+		}
+	}))
+	defer ts.Close()
+
+	// リクエスト送信用の構造体をセット
+	r := Request{
+		URL:      ts.URL,
+		Insecure: true,
+		Auth:     &BearerToken{Token: "token123"},
+	}
+
+	// GETメソッドでリクエストを送信
+	res, _, err := r.Get()
+	// エラーが発生した場合は、Fatalとする
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(res) != "SUCCESS" {
+		t.Fatal("ERROR - GET_HTTPS_BEARER_SUCCESS Get()")
+	}
+}
+
+func Test__GET_HTTPS_CONTEXT_CANCEL(t *testing.T) {
+	// リクエストを受け取った後、応答せず待機するサーバをテストで立てる
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(1 * time.Second)
+	}))
+	defer ts.Close()
+
+	// リクエスト送信用の構造体をセット
+	r := Request{
+		URL:      ts.URL,
+		Insecure: true,
+	}
+
+	// 即座にキャンセルされる context を設定する
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, _, err := r.GetWithContext(ctx); err == nil {
+		t.Fatal("ERROR - GET_HTTPS_CONTEXT_CANCEL GetWithContext()")
+	}
+}
+
+func Test__POST_HTTPS_MULTIPART_SUCCESS(t *testing.T) {
+	// https サーバをテストで立てる
+	ts := httptest.NewTLSServer(multipartHandleFunc)
+	defer ts.Close()
+
+	// リクエスト送信用の構造体をセット
+	r := Request{
+		URL:      ts.URL,
+		Insecure: true,
+	}
+
+	// フォームの値をセット
+	r.Values().Add("key", "value")
+	// 送信するファイルをセット
+	r.AddFile("file", "test.txt", strings.NewReader("hello file"))
+
+	// POSTメソッドでリクエストを送信
+	res, _, err := r.Post()
+	// エラーが発生した場合は、Fatalとする
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(res) != "SUCCESS" {
+		t.Fatal("ERROR - POST_HTTPS_MULTIPART_SUCCESS Post()")
+	}
+}
+
 func Test__PATCH_HTTPS_BASIC_SUCCESS(t *testing.T) {
 	// https サーバをテストで立てる
 	ts := httptest.NewTLSServer(handleFunc)